@@ -1,9 +1,9 @@
 package client
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/mitchellh/mapstructure"
 	"github.com/sillyhatxu/retry-utils"
 	"github.com/sirupsen/logrus"
@@ -27,8 +27,12 @@ func NewSqliteClient(dataSourceName string, opts ...Option) *SqliteClient {
 		connMaxLifetime: 24 * time.Hour,
 		attempts:        3,
 		delay:           200 * time.Millisecond,
-		ddlPath:         "",
 		flyway:          false,
+		autoMigrate:     true,
+		dialect:         SQLiteDialect{},
+		lockRetry:       5,
+		lockTimeout:     500 * time.Millisecond,
+		lockStaleAfter:  10 * time.Minute,
 	}
 	for _, opt := range opts {
 		opt(config)
@@ -65,10 +69,13 @@ func (sc *SqliteClient) Initial() error {
 func (sc *SqliteClient) OpenDataSource() (*sql.DB, error) {
 	var resultDB *sql.DB
 	err := retry.Do(func() error {
-		db, err := sql.Open("sqlite3", sc.dataSourceName)
+		db, err := sql.Open(sc.config.dialect.DriverName(), sc.dataSourceName)
 		if err != nil {
 			return err
 		}
+		db.SetMaxOpenConns(sc.config.maxOpenConns)
+		db.SetMaxIdleConns(sc.config.maxIdleConns)
+		db.SetConnMaxLifetime(sc.config.connMaxLifetime)
 		resultDB = db
 		return nil
 	}, retry.ErrorCallback(func(n uint, err error) {
@@ -101,6 +108,10 @@ func (sc *SqliteClient) GetDB() (*sql.DB, error) {
 }
 
 func (sc *SqliteClient) ExecDDL(ddl string) error {
+	return sc.ExecDDLContext(context.Background(), ddl)
+}
+
+func (sc *SqliteClient) ExecDDLContext(ctx context.Context, ddl string) error {
 	db, err := sc.GetDB()
 	if err != nil {
 		return err
@@ -108,64 +119,57 @@ func (sc *SqliteClient) ExecDDL(ddl string) error {
 	logrus.Infof("exec ddl : ")
 	logrus.Infof(ddl)
 	logrus.Infof("--------------------")
-	_, err = db.Exec(ddl)
+	_, err = db.ExecContext(ctx, ddl)
 	return err
 }
 
 func (sc *SqliteClient) Find(sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	return sc.FindContext(context.Background(), sql, args...)
+}
+
+func (sc *SqliteClient) FindContext(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	sql = sc.config.dialect.Rebind(sql)
 	db, err := sc.GetDB()
 	if err != nil {
 		return nil, err
 	}
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		logrus.Errorf("sqlite client get transaction error. %v", err)
 		return nil, err
 	}
 	defer tx.Commit()
-	rows, err := tx.Query(sql, args...)
+	rows, err := tx.QueryContext(ctx, sql, args...)
 	if err != nil {
 		logrus.Errorf("query error. %v", err)
 		return nil, err
 	}
 	defer rows.Close()
-	columns, err := rows.Columns()
+	results, err := scanRows(rows)
 	if err != nil {
-		logrus.Errorf("rows.Columns() error. %v", err)
+		logrus.Errorf("scan rows error. %v", err)
 		return nil, err
 	}
-	values := make([][]byte, len(columns))
-	scans := make([]interface{}, len(columns))
-	for i := range values {
-		scans[i] = &values[i]
-	}
-	var results []map[string]interface{}
-	for rows.Next() {
-		if err := rows.Scan(scans...); err != nil {
-			return nil, err
-		}
-		row := make(map[string]interface{})
-		for k, v := range values {
-			key := columns[k]
-			row[key] = string(v)
-		}
-		results = append(results, row)
-	}
 	return results, nil
 }
 
 func (sc *SqliteClient) Insert(sql string, args ...interface{}) (int64, error) {
+	return sc.InsertContext(context.Background(), sql, args...)
+}
+
+func (sc *SqliteClient) InsertContext(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	sql = sc.config.dialect.Rebind(sql)
 	db, err := sc.GetDB()
 	if err != nil {
 		return 0, nil
 	}
-	stm, err := db.Prepare(sql)
+	stm, err := db.PrepareContext(ctx, sql)
 	if err != nil {
 		logrus.Errorf("prepare sqlite error. %v", err)
 		return 0, err
 	}
 	defer stm.Close()
-	result, err := stm.Exec(args...)
+	result, err := stm.ExecContext(ctx, args...)
 	if err != nil {
 		logrus.Errorf("insert data error. %v", err)
 		return 0, err
@@ -174,17 +178,22 @@ func (sc *SqliteClient) Insert(sql string, args ...interface{}) (int64, error) {
 }
 
 func (sc *SqliteClient) Update(sql string, args ...interface{}) (int64, error) {
+	return sc.UpdateContext(context.Background(), sql, args...)
+}
+
+func (sc *SqliteClient) UpdateContext(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	sql = sc.config.dialect.Rebind(sql)
 	db, err := sc.GetDB()
 	if err != nil {
 		return 0, nil
 	}
-	stm, err := db.Prepare(sql)
+	stm, err := db.PrepareContext(ctx, sql)
 	if err != nil {
 		logrus.Errorf("prepare sqlite error. %v", err)
 		return 0, err
 	}
 	defer stm.Close()
-	result, err := stm.Exec(args...)
+	result, err := stm.ExecContext(ctx, args...)
 	if err != nil {
 		logrus.Errorf("update data error. %v", err)
 		return 0, err
@@ -193,17 +202,22 @@ func (sc *SqliteClient) Update(sql string, args ...interface{}) (int64, error) {
 }
 
 func (sc *SqliteClient) Delete(sql string, args ...interface{}) (int64, error) {
+	return sc.DeleteContext(context.Background(), sql, args...)
+}
+
+func (sc *SqliteClient) DeleteContext(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	sql = sc.config.dialect.Rebind(sql)
 	db, err := sc.GetDB()
 	if err != nil {
 		return 0, nil
 	}
-	stm, err := db.Prepare(sql)
+	stm, err := db.PrepareContext(ctx, sql)
 	if err != nil {
 		logrus.Errorf("prepare sqlite error. %v", err)
 		return 0, err
 	}
 	defer stm.Close()
-	result, err := stm.Exec(args...)
+	result, err := stm.ExecContext(ctx, args...)
 	if err != nil {
 		logrus.Errorf("delete data error. %v", err)
 		return 0, err
@@ -214,11 +228,18 @@ func (sc *SqliteClient) Delete(sql string, args ...interface{}) (int64, error) {
 type TransactionCallback func(*sql.Tx) error
 
 func (sc *SqliteClient) Transaction(callback TransactionCallback) error {
+	return sc.TransactionContext(context.Background(), nil, callback)
+}
+
+// TransactionContext runs callback inside a transaction started with opts,
+// e.g. &sql.TxOptions{ReadOnly: true} or a specific isolation level. opts may
+// be nil to use the driver's default.
+func (sc *SqliteClient) TransactionContext(ctx context.Context, opts *sql.TxOptions, callback TransactionCallback) error {
 	db, err := sc.GetDB()
 	if err != nil {
 		return nil
 	}
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
 		logrus.Errorf("sqlite client get transaction error. %v", err)
 		return err
@@ -235,11 +256,16 @@ func (sc *SqliteClient) Transaction(callback TransactionCallback) error {
 type FieldFunc func(rows *sql.Rows) error
 
 func (sc *SqliteClient) Query(query string, fieldFunc FieldFunc, args ...interface{}) error {
+	return sc.QueryContext(context.Background(), query, fieldFunc, args...)
+}
+
+func (sc *SqliteClient) QueryContext(ctx context.Context, query string, fieldFunc FieldFunc, args ...interface{}) error {
+	query = sc.config.dialect.Rebind(query)
 	db, err := sc.GetDB()
 	if err != nil {
 		return err
 	}
-	rows, err := db.Query(query, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -254,7 +280,11 @@ func (sc *SqliteClient) Query(query string, fieldFunc FieldFunc, args ...interfa
 }
 
 func (sc *SqliteClient) FindMapFirst(sql string, args ...interface{}) (map[string]interface{}, error) {
-	array, err := sc.FindMapArray(sql, args...)
+	return sc.FindMapFirstContext(context.Background(), sql, args...)
+}
+
+func (sc *SqliteClient) FindMapFirstContext(ctx context.Context, sql string, args ...interface{}) (map[string]interface{}, error) {
+	array, err := sc.FindMapArrayContext(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -265,58 +295,47 @@ func (sc *SqliteClient) FindMapFirst(sql string, args ...interface{}) (map[strin
 }
 
 func (sc *SqliteClient) FindMapArray(sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	return sc.FindMapArrayContext(context.Background(), sql, args...)
+}
+
+func (sc *SqliteClient) FindMapArrayContext(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	sql = sc.config.dialect.Rebind(sql)
 	db, err := sc.GetDB()
 	if err != nil {
 		return nil, err
 	}
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Println("sqlite client get transaction error.", err)
 		return nil, err
 	}
 	defer tx.Commit()
-	rows, err := tx.Query(sql, args...)
+	rows, err := tx.QueryContext(ctx, sql, args...)
 	if err != nil {
 		log.Println("Query error.", err)
 		return nil, err
 	}
 	defer rows.Close()
-	columns, err := rows.Columns()
+	results, err := scanRows(rows)
 	if err != nil {
-		log.Println("rows.Columns() error.", err)
+		log.Println("scan rows error.", err)
 		return nil, err
 	}
-	//values是每个列的值，这里获取到byte里
-	values := make([][]byte, len(columns))
-	//query.Scan的参数，因为每次查询出来的列是不定长的，用len(cols)定住当次查询的长度
-	scans := make([]interface{}, len(columns))
-	//让每一行数据都填充到[][]byte里面
-	for i := range values {
-		scans[i] = &values[i]
-	}
-	//最后得到的map
-	var results []map[string]interface{}
-	for rows.Next() { //循环，让游标往下推
-		if err := rows.Scan(scans...); err != nil { //query.Scan查询出来的不定长值放到scans[i] = &values[i],也就是每行都放在values里
-			return nil, err
-		}
-		row := make(map[string]interface{}) //每行数据
-		for k, v := range values {          //每行数据是放在values里面，现在把它挪到row里
-			key := columns[k]
-			//valueType := reflect.TypeOf(v)
-			//log.Info(valueType)
-			row[key] = string(v)
-		}
-		results = append(results, row)
-	}
 	return results, nil
 }
 
 func (sc *SqliteClient) FindList(sql string, input interface{}, args ...interface{}) error {
-	results, err := sc.FindMapArray(sql, args...)
+	return sc.FindListContext(context.Background(), sql, input, args...)
+}
+
+func (sc *SqliteClient) FindListContext(ctx context.Context, sql string, input interface{}, args ...interface{}) error {
+	results, err := sc.FindMapArrayContext(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
+	if handled, err := decodeRowsByTag(results, input); handled {
+		return err
+	}
 	config := &mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeHookFunc("2006-01-02 15:04:05"),
 		WeaklyTypedInput: true,
@@ -334,7 +353,11 @@ func (sc *SqliteClient) FindList(sql string, input interface{}, args ...interfac
 }
 
 func (sc *SqliteClient) FindListByConfig(sql string, config *mapstructure.DecoderConfig, args ...interface{}) error {
-	results, err := sc.FindMapArray(sql, args...)
+	return sc.FindListByConfigContext(context.Background(), sql, config, args...)
+}
+
+func (sc *SqliteClient) FindListByConfigContext(ctx context.Context, sql string, config *mapstructure.DecoderConfig, args ...interface{}) error {
+	results, err := sc.FindMapArrayContext(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
@@ -350,10 +373,17 @@ func (sc *SqliteClient) FindListByConfig(sql string, config *mapstructure.Decode
 }
 
 func (sc *SqliteClient) FindFirst(sql string, input interface{}, args ...interface{}) error {
-	result, err := sc.FindMapFirst(sql, args...)
+	return sc.FindFirstContext(context.Background(), sql, input, args...)
+}
+
+func (sc *SqliteClient) FindFirstContext(ctx context.Context, sql string, input interface{}, args ...interface{}) error {
+	result, err := sc.FindMapFirstContext(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
+	if handled, err := decodeRowByTagInput(result, input); handled {
+		return err
+	}
 	config := &mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeHookFunc("2006-01-02 15:04:05"),
 		WeaklyTypedInput: true,
@@ -371,7 +401,11 @@ func (sc *SqliteClient) FindFirst(sql string, input interface{}, args ...interfa
 }
 
 func (sc *SqliteClient) FindFirstByConfig(sql string, config *mapstructure.DecoderConfig, args ...interface{}) error {
-	result, err := sc.FindMapFirst(sql, args...)
+	return sc.FindFirstByConfigContext(context.Background(), sql, config, args...)
+}
+
+func (sc *SqliteClient) FindFirstByConfigContext(ctx context.Context, sql string, config *mapstructure.DecoderConfig, args ...interface{}) error {
+	result, err := sc.FindMapFirstContext(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
@@ -387,18 +421,23 @@ func (sc *SqliteClient) FindFirstByConfig(sql string, config *mapstructure.Decod
 }
 
 func (sc *SqliteClient) Count(sql string, args ...interface{}) (int64, error) {
+	return sc.CountContext(context.Background(), sql, args...)
+}
+
+func (sc *SqliteClient) CountContext(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	sql = sc.config.dialect.Rebind(sql)
 	db, err := sc.GetDB()
 	if err != nil {
 		return 0, err
 	}
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Println("sqlite client get connection error.", err)
 		return 0, err
 	}
 	defer tx.Commit()
 	var count int64
-	countErr := tx.QueryRow(sql, args...).Scan(&count)
+	countErr := tx.QueryRowContext(ctx, sql, args...).Scan(&count)
 	if countErr != nil {
 		log.Println("Query count error.", err)
 		return 0, err
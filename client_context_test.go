@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenDataSource_PoolSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pool.db")
+	sc := NewSqliteClient(path, MaxOpenConns(7), MaxIdleConns(2), ConnMaxLifetime(10*time.Millisecond))
+	db, err := sc.OpenDataSource()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var one int
+			db.QueryRow("select 1").Scan(&one)
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, db.Stats().Idle, 2)
+
+	time.Sleep(20 * time.Millisecond)
+	var one int
+	db.QueryRow("select 1").Scan(&one)
+	assert.Greater(t, db.Stats().MaxLifetimeClosed, int64(0))
+}
+
+func TestFindContext_CanceledContextAborts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cancel.db")
+	sc := NewSqliteClient(path)
+	assert.Nil(t, sc.Initial())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := sc.FindContext(ctx, "select 1")
+	assert.Error(t, err)
+}
+
+// capturingConn is a minimal driver.Conn that records the TxOptions passed
+// to BeginTx, used to verify TransactionContext forwards them unchanged.
+type capturingConn struct {
+	captured *driver.TxOptions
+}
+
+func (c *capturingConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *capturingConn) Close() error                              { return nil }
+func (c *capturingConn) Begin() (driver.Tx, error)                 { return capturingTx{}, nil }
+func (c *capturingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	*c.captured = opts
+	return capturingTx{}, nil
+}
+
+type capturingTx struct{}
+
+func (capturingTx) Commit() error   { return nil }
+func (capturingTx) Rollback() error { return nil }
+
+type capturingDriver struct {
+	captured *driver.TxOptions
+}
+
+func (d capturingDriver) Open(name string) (driver.Conn, error) {
+	return &capturingConn{captured: d.captured}, nil
+}
+
+// capturingDialect delegates everything but DriverName to SQLiteDialect, so
+// connection setup talks to the registered capturingDriver instead of the
+// real sqlite3 driver.
+type capturingDialect struct {
+	SQLiteDialect
+	driverName string
+}
+
+func (d capturingDialect) DriverName() string { return d.driverName }
+
+var registerCapturingDriverOnce sync.Once
+var capturedTxOptions driver.TxOptions
+
+func registerCapturingDriver() {
+	registerCapturingDriverOnce.Do(func() {
+		sql.Register("capturing-tx-driver", capturingDriver{captured: &capturedTxOptions})
+	})
+}
+
+func TestTransactionContext_PassesTxOptions(t *testing.T) {
+	registerCapturingDriver()
+	capturedTxOptions = driver.TxOptions{}
+
+	sc := NewSqliteClient("capturing-tx-driver-dsn", WithDialect(capturingDialect{driverName: "capturing-tx-driver"}))
+	assert.Nil(t, sc.Initial())
+	err := sc.TransactionContext(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, capturedTxOptions.ReadOnly)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+
+	client "github.com/sillyhatxu/sqlite-client"
+)
+
+func main() {
+	dataSourceName := flag.String("db", "", "data source name, e.g. path to the sqlite database file")
+	ddlPath := flag.String("path", "", "directory containing versioned migration files")
+	command := flag.String("command", "status", "migrate command: up, down, force or status")
+	targetVersion := flag.Int64("version", 0, "target version for the up command (0 = latest)")
+	steps := flag.Int("steps", 1, "number of versions to roll back for the down command")
+	flag.Parse()
+
+	if *dataSourceName == "" || *ddlPath == "" {
+		fmt.Fprintln(os.Stderr, "sqlite-migrate: -db and -path are required")
+		os.Exit(1)
+	}
+
+	sc := client.NewSqliteClient(*dataSourceName, client.DDLPath(*ddlPath), client.NoAutoMigrate())
+	if err := sc.Initial(); err != nil {
+		fmt.Fprintf(os.Stderr, "sqlite-migrate: initial error. %v\n", err)
+		os.Exit(1)
+	}
+
+	switch *command {
+	case "up":
+		target := *targetVersion
+		if target == 0 {
+			target = math.MaxInt64
+		}
+		if err := sc.Migrate(target); err != nil {
+			fmt.Fprintf(os.Stderr, "sqlite-migrate: migrate error. %v\n", err)
+			os.Exit(1)
+		}
+	case "down":
+		if err := sc.Rollback(*steps); err != nil {
+			fmt.Fprintf(os.Stderr, "sqlite-migrate: rollback error. %v\n", err)
+			os.Exit(1)
+		}
+	case "force":
+		if err := sc.Force(*targetVersion); err != nil {
+			fmt.Fprintf(os.Stderr, "sqlite-migrate: force error. %v\n", err)
+			os.Exit(1)
+		}
+	case "status":
+		version, err := sc.Status()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sqlite-migrate: status error. %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("current schema version: %d\n", version)
+	default:
+		fmt.Fprintf(os.Stderr, "sqlite-migrate: unknown command %q\n", *command)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,55 @@
+package client
+
+import "fmt"
+
+// Dialect captures the bits of SQL that differ between database engines.
+type Dialect interface {
+	Name() string
+	DriverName() string
+	TableExistsQuery() string
+	SchemaVersionDDL() string
+	SchemaLockDDL() string
+	AcquireLockSQL() string
+	LockBeginStatement() string
+	Rebind(query string) string
+}
+
+// rebindQuestionMarks replaces every "?" placeholder in query with the
+// dialect's positional placeholder, e.g. "$1", "$2", ... for Postgres. A "?"
+// inside a quoted string literal is left untouched.
+func rebindQuestionMarks(query string, placeholder func(position int) string) string {
+	var result []byte
+	position := 0
+	var inQuote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if inQuote != 0 {
+			result = append(result, c)
+			if c == '\\' && i+1 < len(query) {
+				i++
+				result = append(result, query[i])
+				continue
+			}
+			if c == inQuote {
+				inQuote = 0
+			}
+			continue
+		}
+		if c == '\'' || c == '"' {
+			inQuote = c
+			result = append(result, c)
+			continue
+		}
+		if c != '?' {
+			result = append(result, c)
+			continue
+		}
+		position++
+		result = append(result, []byte(placeholder(position))...)
+	}
+	return string(result)
+}
+
+func dollarPlaceholder(position int) string {
+	return fmt.Sprintf("$%d", position)
+}
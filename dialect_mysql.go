@@ -0,0 +1,56 @@
+package client
+
+import (
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const mysqlTableExistsQuery = `
+		SELECT count(1) FROM information_schema.tables WHERE table_schema = database() AND table_name = ?
+	`
+
+const mysqlSchemaVersionDDL = `
+		CREATE TABLE IF NOT EXISTS schema_version
+		(
+		  id             INTEGER AUTO_INCREMENT PRIMARY KEY,
+		  version        BIGINT   NOT NULL,
+		  description    TEXT     NOT NULL,
+		  direction      TEXT     NOT NULL,
+		  script         TEXT     NOT NULL,
+		  checksum       TEXT     NOT NULL,
+		  execution_time TEXT     NOT NULL,
+		  status         TEXT     NOT NULL,
+		  created_time   datetime default current_timestamp
+		);
+	`
+
+const mysqlSchemaLockDDL = `
+		CREATE TABLE IF NOT EXISTS schema_lock
+		(
+		  id          INTEGER  PRIMARY KEY,
+		  pid         INTEGER  NOT NULL,
+		  hostname    TEXT     NOT NULL,
+		  acquired_at datetime NOT NULL
+		);
+	`
+
+const mysqlAcquireLockSQL = `
+		INSERT IGNORE INTO schema_lock (id, pid, hostname, acquired_at) VALUES (?, ?, ?, ?)
+	`
+
+// MySQLDialect is a Dialect backed by go-sql-driver/mysql.
+type MySQLDialect struct{}
+
+func (MySQLDialect) Name() string       { return "mysql" }
+func (MySQLDialect) DriverName() string { return "mysql" }
+
+func (MySQLDialect) TableExistsQuery() string { return mysqlTableExistsQuery }
+
+func (MySQLDialect) SchemaVersionDDL() string { return mysqlSchemaVersionDDL }
+
+func (MySQLDialect) SchemaLockDDL() string { return mysqlSchemaLockDDL }
+
+func (MySQLDialect) AcquireLockSQL() string { return mysqlAcquireLockSQL }
+
+func (MySQLDialect) LockBeginStatement() string { return "BEGIN" }
+
+func (MySQLDialect) Rebind(query string) string { return query }
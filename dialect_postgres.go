@@ -0,0 +1,59 @@
+package client
+
+import (
+	_ "github.com/lib/pq"
+)
+
+const postgresTableExistsQuery = `
+		SELECT count(1) FROM information_schema.tables WHERE table_name = $1
+	`
+
+const postgresSchemaVersionDDL = `
+		CREATE TABLE IF NOT EXISTS schema_version
+		(
+		  id             SERIAL PRIMARY KEY,
+		  version        BIGINT    NOT NULL,
+		  description    TEXT      NOT NULL,
+		  direction      TEXT      NOT NULL,
+		  script         TEXT      NOT NULL,
+		  checksum       TEXT      NOT NULL,
+		  execution_time TEXT      NOT NULL,
+		  status         TEXT      NOT NULL,
+		  created_time   timestamp default current_timestamp
+		);
+	`
+
+const postgresSchemaLockDDL = `
+		CREATE TABLE IF NOT EXISTS schema_lock
+		(
+		  id          INTEGER PRIMARY KEY,
+		  pid         INTEGER   NOT NULL,
+		  hostname    TEXT      NOT NULL,
+		  acquired_at timestamp NOT NULL
+		);
+	`
+
+const postgresAcquireLockSQL = `
+		INSERT INTO schema_lock (id, pid, hostname, acquired_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+// PostgresDialect is a Dialect backed by lib/pq.
+type PostgresDialect struct{}
+
+func (PostgresDialect) Name() string       { return "postgres" }
+func (PostgresDialect) DriverName() string { return "postgres" }
+
+func (PostgresDialect) TableExistsQuery() string { return postgresTableExistsQuery }
+
+func (PostgresDialect) SchemaVersionDDL() string { return postgresSchemaVersionDDL }
+
+func (PostgresDialect) SchemaLockDDL() string { return postgresSchemaLockDDL }
+
+func (PostgresDialect) AcquireLockSQL() string { return postgresAcquireLockSQL }
+
+func (PostgresDialect) LockBeginStatement() string { return "BEGIN" }
+
+func (PostgresDialect) Rebind(query string) string {
+	return rebindQuestionMarks(query, dollarPlaceholder)
+}
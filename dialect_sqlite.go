@@ -0,0 +1,57 @@
+package client
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteTableExistsQuery = `
+		SELECT count(1) FROM sqlite_master WHERE type='table' AND name = ?
+	`
+
+const sqliteSchemaVersionDDL = `
+		CREATE TABLE IF NOT EXISTS schema_version
+		(
+		  id             INTEGER PRIMARY KEY AUTOINCREMENT,
+		  version        INTEGER NOT NULL,
+		  description    TEXT    NOT NULL,
+		  direction      TEXT    NOT NULL,
+		  script         TEXT    NOT NULL,
+		  checksum       TEXT    NOT NULL,
+		  execution_time TEXT    NOT NULL,
+		  status         TEXT    NOT NULL,
+		  created_time   datetime default current_timestamp
+		);
+	`
+
+const sqliteSchemaLockDDL = `
+		CREATE TABLE IF NOT EXISTS schema_lock
+		(
+		  id          INTEGER PRIMARY KEY,
+		  pid         INTEGER  NOT NULL,
+		  hostname    TEXT     NOT NULL,
+		  acquired_at datetime NOT NULL
+		);
+	`
+
+const sqliteAcquireLockSQL = `
+		INSERT INTO schema_lock (id, pid, hostname, acquired_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO NOTHING
+	`
+
+// SQLiteDialect is the default Dialect, backed by mattn/go-sqlite3.
+type SQLiteDialect struct{}
+
+func (SQLiteDialect) Name() string       { return "sqlite3" }
+func (SQLiteDialect) DriverName() string { return "sqlite3" }
+
+func (SQLiteDialect) TableExistsQuery() string { return sqliteTableExistsQuery }
+
+func (SQLiteDialect) SchemaVersionDDL() string { return sqliteSchemaVersionDDL }
+
+func (SQLiteDialect) SchemaLockDDL() string { return sqliteSchemaLockDDL }
+
+func (SQLiteDialect) AcquireLockSQL() string { return sqliteAcquireLockSQL }
+
+func (SQLiteDialect) LockBeginStatement() string { return "BEGIN EXCLUSIVE" }
+
+func (SQLiteDialect) Rebind(query string) string { return query }
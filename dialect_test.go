@@ -0,0 +1,43 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresDialect_Rebind(t *testing.T) {
+	dialect := PostgresDialect{}
+	rebound := dialect.Rebind(`select * from t where note = 'what?' AND id = ?`)
+	assert.Equal(t, `select * from t where note = 'what?' AND id = $1`, rebound)
+}
+
+func TestPostgresDialect_Rebind_MultipleParams(t *testing.T) {
+	dialect := PostgresDialect{}
+	rebound := dialect.Rebind(`update t set a = ?, b = ? where id = ?`)
+	assert.Equal(t, `update t set a = $1, b = $2 where id = $3`, rebound)
+}
+
+func TestPostgresDialect_Rebind_BackslashEscapedQuote(t *testing.T) {
+	dialect := PostgresDialect{}
+	rebound := dialect.Rebind(`select * from t where note = 'it\'s ? thing' AND id = ?`)
+	assert.Equal(t, `select * from t where note = 'it\'s ? thing' AND id = $1`, rebound)
+}
+
+func TestPostgresDialect_Rebind_DoubleQuotedIdentifier(t *testing.T) {
+	dialect := PostgresDialect{}
+	rebound := dialect.Rebind(`select "weird?column" from t where id = ?`)
+	assert.Equal(t, `select "weird?column" from t where id = $1`, rebound)
+}
+
+func TestSQLiteDialect_Rebind_Passthrough(t *testing.T) {
+	dialect := SQLiteDialect{}
+	query := `select * from t where note = 'what?' AND id = ?`
+	assert.Equal(t, query, dialect.Rebind(query))
+}
+
+func TestMySQLDialect_Rebind_Passthrough(t *testing.T) {
+	dialect := MySQLDialect{}
+	query := `select * from t where note = 'what?' AND id = ?`
+	assert.Equal(t, query, dialect.Rebind(query))
+}
@@ -1,13 +1,11 @@
 package client
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"github.com/sirupsen/logrus"
-	"hash/fnv"
-	"io/ioutil"
-	"os"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -17,28 +15,22 @@ const (
 
 	SchemaVersionStatusError = `ERROR`
 
-	SqliteMasterSQL = `
-		SELECT count(1) FROM sqlite_master WHERE type='table' AND name = ?
-	`
+	DirectionUp = `up`
+
+	DirectionDown = `down`
 
 	InsertSchemaVersionSQL = `
-		INSERT INTO schema_version (script, checksum, execution_time, status) values (?, ?, ?, ?)
-	`
-	DDLSchemaVersion = `
-		CREATE TABLE IF NOT EXISTS schema_version
-		(
-		  id             INTEGER PRIMARY KEY AUTOINCREMENT,
-		  script         TEXT    NOT NULL,
-		  checksum       TEXT    NOT NULL,
-		  execution_time TEXT    NOT NULL,
-		  status         TEXT    NOT NULL,
-		  created_time   datetime default current_timestamp
-		);
+		INSERT INTO schema_version (version, description, direction, script, checksum, execution_time, status) values (?, ?, ?, ?, ?, ?, ?)
 	`
 )
 
+// SchemaVersion is a row of the schema_version bookkeeping table: one row per
+// migration script (up or down) that has been applied.
 type SchemaVersion struct {
 	Id            int64
+	Version       int64
+	Description   string
+	Direction     string
 	Script        string
 	Checksum      string
 	ExecutionTime string
@@ -46,31 +38,21 @@ type SchemaVersion struct {
 	CreatedTime   time.Time
 }
 
-func (sc *SqliteClient) findByScript(script string, svArray []SchemaVersion) (bool, *SchemaVersion) {
-	for _, sv := range svArray {
-		if sv.Script == script {
-			return true, &sv
-		}
-	}
-	return false, nil
-}
-
+// hasError blocks further migrations while a version's most recent record is
+// an ERROR, i.e. hasn't since been resolved by a later run (notably Force).
 func (sc *SqliteClient) hasError(svArray []SchemaVersion) error {
-	for _, sv := range svArray {
-		if sv.Status == SchemaVersionStatusError {
+	for _, version := range distinctVersions(svArray) {
+		sv, ok := latestRecordByVersion(version, svArray)
+		if ok && sv.Status == SchemaVersionStatusError {
 			return fmt.Errorf("schema version has abnormal state. You need to prioritize exceptional states. %#v", sv)
 		}
 	}
 	return nil
 }
 
-func hash64(s string) (uint64, error) {
-	h := fnv.New64()
-	_, err := h.Write([]byte(s))
-	if err != nil {
-		return 0, err
-	}
-	return h.Sum64(), nil
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
 }
 
 func shortDur(d time.Duration) string {
@@ -92,6 +74,13 @@ func (sc *SqliteClient) initialFlayway() (err error) {
 	if err != nil {
 		return err
 	}
+	if !sc.config.autoMigrate {
+		return nil
+	}
+	if err := sc.acquireMigrationLock(); err != nil {
+		return err
+	}
+	defer sc.releaseMigrationLock()
 	err = sc.executeFlayway()
 	if err != nil {
 		return err
@@ -99,10 +88,12 @@ func (sc *SqliteClient) initialFlayway() (err error) {
 	return nil
 }
 
+// executeFlayway applies every pending "up" migration under the configured
+// migration source, in ascending version order.
 func (sc *SqliteClient) executeFlayway() error {
-	files, err := ioutil.ReadDir(sc.config.ddlPath)
+	migrations, err := sc.loadMigrationFiles()
 	if err != nil {
-		return nil
+		return err
 	}
 	svArray, err := sc.SchemaVersionArray()
 	if err != nil {
@@ -112,52 +103,72 @@ func (sc *SqliteClient) executeFlayway() error {
 	if err != nil {
 		return err
 	}
-	for _, f := range files {
-		err := sc.readFile(f, svArray)
-		if err != nil {
+	for _, mf := range migrations {
+		if mf.Direction != DirectionUp {
+			continue
+		}
+		if err := sc.applyMigration(mf, svArray); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (sc *SqliteClient) readFile(fileInfo os.FileInfo, svArray []SchemaVersion) error {
-	b, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", sc.config.ddlPath, fileInfo.Name()))
-	if err != nil {
-		return err
-	}
-	checksum, err := hash64(string(b))
+// applyMigration executes a single up migration unless it has already been
+// applied with a matching checksum, in which case it is a no-op.
+func (sc *SqliteClient) applyMigration(mf migrationFile, svArray []SchemaVersion) error {
+	content, err := sc.config.source.Read(mf.Name)
 	if err != nil {
 		return err
 	}
-	exist, sv := sc.findByScript(fileInfo.Name(), svArray)
-	if exist {
-		if sv.Checksum != strconv.FormatUint(checksum, 10) {
+	checksum := sha256Hex(string(content))
+	if sv, applied := latestUpRecord(mf.Version, svArray); applied {
+		if sv.Checksum != checksum {
 			return fmt.Errorf("sql file has been changed. %#v", sv)
 		}
-		return nil
+		if isVersionApplied(mf.Version, svArray) {
+			return nil
+		}
+	}
+	return sc.execMigrationTx(mf, checksum, string(content))
+}
+
+// execMigrationTx runs a migration script inside a transaction, so a failed
+// statement leaves no partial DDL behind, and records the outcome in
+// schema_version regardless of success.
+func (sc *SqliteClient) execMigrationTx(mf migrationFile, checksum string, content string) error {
+	db, err := sc.GetDB()
+	if err != nil {
+		return err
 	}
 	execTime := time.Now()
-	schemaVersion := SchemaVersion{
-		Script:   fileInfo.Name(),
-		Checksum: strconv.FormatUint(checksum, 10),
-		Status:   SchemaVersionStatusError,
-	}
-	err = sc.ExecDDL(string(b))
-	if err == nil {
-		schemaVersion.Status = SchemaVersionStatusSuccess
-	}
-	elapsed := time.Since(execTime)
-	schemaVersion.ExecutionTime = shortDur(elapsed)
-	sc.insertSchemaVersion(schemaVersion)
+	tx, err := db.Begin()
 	if err != nil {
 		return err
 	}
-	return nil
+	_, execErr := tx.Exec(content)
+	status := SchemaVersionStatusSuccess
+	if execErr != nil {
+		status = SchemaVersionStatusError
+		tx.Rollback()
+	} else if commitErr := tx.Commit(); commitErr != nil {
+		status = SchemaVersionStatusError
+		execErr = commitErr
+	}
+	sc.insertSchemaVersion(SchemaVersion{
+		Version:       mf.Version,
+		Description:   mf.Description,
+		Direction:     mf.Direction,
+		Script:        mf.Name,
+		Checksum:      checksum,
+		ExecutionTime: shortDur(time.Since(execTime)),
+		Status:        status,
+	})
+	return execErr
 }
 
 func (sc *SqliteClient) insertSchemaVersion(schemaVersion SchemaVersion) {
-	_, err := sc.Insert(InsertSchemaVersionSQL, schemaVersion.Script, schemaVersion.Checksum, schemaVersion.ExecutionTime, schemaVersion.Status)
+	_, err := sc.Insert(InsertSchemaVersionSQL, schemaVersion.Version, schemaVersion.Description, schemaVersion.Direction, schemaVersion.Script, schemaVersion.Checksum, schemaVersion.ExecutionTime, schemaVersion.Status)
 	if err != nil {
 		logrus.Errorf("insert schema version error. %v", err)
 	}
@@ -171,12 +182,12 @@ func (sc *SqliteClient) initialSchemaVersion() error {
 	if exist {
 		return nil
 	}
-	return sc.ExecDDL(DDLSchemaVersion)
+	return sc.ExecDDL(sc.config.dialect.SchemaVersionDDL())
 }
 
 func (sc *SqliteClient) HasTable(table string) (bool, error) {
 	var count int
-	err := sc.Query(SqliteMasterSQL, func(rows *sql.Rows) error {
+	err := sc.Query(sc.config.dialect.TableExistsQuery(), func(rows *sql.Rows) error {
 		return rows.Scan(&count)
 	}, table)
 	if err != nil {
@@ -189,7 +200,7 @@ func (sc *SqliteClient) SchemaVersionArray() ([]SchemaVersion, error) {
 	var svArray []SchemaVersion
 	err := sc.Query(`select * from schema_version`, func(rows *sql.Rows) error {
 		var sv SchemaVersion
-		err := rows.Scan(&sv.Id, &sv.Script, &sv.Checksum, &sv.ExecutionTime, &sv.Status, &sv.CreatedTime)
+		err := rows.Scan(&sv.Id, &sv.Version, &sv.Description, &sv.Direction, &sv.Script, &sv.Checksum, &sv.ExecutionTime, &sv.Status, &sv.CreatedTime)
 		svArray = append(svArray, sv)
 		return err
 	})
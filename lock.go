@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	retry "github.com/sillyhatxu/retry-utils"
+	"github.com/sirupsen/logrus"
+	"os"
+	"time"
+)
+
+const schemaLockID = 1
+
+// reclaimStaleLockSQL deletes the schema_lock row only if it's older than
+// the caller's staleness threshold, leaving a live lock untouched.
+const reclaimStaleLockSQL = `DELETE FROM schema_lock WHERE id = ? AND acquired_at < ?`
+
+// acquireMigrationLock takes an exclusive, cross-process lock via a sentinel
+// row in schema_lock before scanning and applying migrations. A row older
+// than lockStaleAfter is reclaimed first, in case the holder died.
+func (sc *SqliteClient) acquireMigrationLock() error {
+	if err := sc.ensureSchemaLockTable(); err != nil {
+		return err
+	}
+	db, err := sc.GetDB()
+	if err != nil {
+		return err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return retry.Do(func() error {
+		return sc.tryAcquireMigrationLock(db, hostname)
+	}, retry.Attempts(sc.config.lockRetry), retry.Delay(sc.config.lockTimeout), retry.ErrorCallback(func(n uint, err error) {
+		logrus.Errorf("retry [%d] acquire schema lock error. %v", n, err)
+	}))
+}
+
+// tryAcquireMigrationLock runs BEGIN/INSERT/COMMIT on a single pinned
+// connection, since BEGIN EXCLUSIVE only holds for the connection it ran on.
+func (sc *SqliteClient) tryAcquireMigrationLock(db *sql.DB, hostname string) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, sc.config.dialect.LockBeginStatement()); err != nil {
+		return err
+	}
+	staleBefore := time.Now().Add(-sc.config.lockStaleAfter)
+	if _, err := conn.ExecContext(ctx, sc.config.dialect.Rebind(reclaimStaleLockSQL), schemaLockID, staleBefore); err != nil {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return err
+	}
+	result, err := conn.ExecContext(ctx, sc.config.dialect.Rebind(sc.config.dialect.AcquireLockSQL()), schemaLockID, os.Getpid(), hostname, time.Now())
+	if err != nil {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return err
+	}
+	if affected == 0 {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return fmt.Errorf("schema lock is held by another process")
+	}
+	_, err = conn.ExecContext(ctx, "COMMIT")
+	return err
+}
+
+func (sc *SqliteClient) releaseMigrationLock() error {
+	_, err := sc.Delete(`DELETE FROM schema_lock WHERE id = ?`, schemaLockID)
+	return err
+}
+
+func (sc *SqliteClient) ensureSchemaLockTable() error {
+	exist, err := sc.HasTable("schema_lock")
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	return sc.ExecDDL(sc.config.dialect.SchemaLockDDL())
+}
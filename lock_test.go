@@ -0,0 +1,50 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLockTestClient(t *testing.T, path string, opts ...Option) *SqliteClient {
+	sc := NewSqliteClient(path, opts...)
+	assert.Nil(t, sc.Initial())
+	return sc
+}
+
+func TestMigrationLock_AcquireReleaseCycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock.db")
+	sc := newLockTestClient(t, path)
+
+	assert.Nil(t, sc.acquireMigrationLock())
+	assert.Nil(t, sc.releaseMigrationLock())
+
+	assert.Nil(t, sc.acquireMigrationLock())
+	assert.Nil(t, sc.releaseMigrationLock())
+}
+
+func TestMigrationLock_HeldLockBlocksSecondAcquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock.db")
+	holder := newLockTestClient(t, path)
+	assert.Nil(t, holder.acquireMigrationLock())
+	defer holder.releaseMigrationLock()
+
+	contender := newLockTestClient(t, path, LockRetry(2), LockTimeout(10*time.Millisecond))
+	assert.Error(t, contender.acquireMigrationLock())
+}
+
+func TestMigrationLock_StaleLockIsReclaimed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock.db")
+	sc := newLockTestClient(t, path, LockStaleAfter(10*time.Millisecond))
+	assert.Nil(t, sc.ensureSchemaLockTable())
+
+	_, err := sc.Insert(`INSERT INTO schema_lock (id, pid, hostname, acquired_at) VALUES (?, ?, ?, ?)`,
+		schemaLockID, os.Getpid(), "stale-host", time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+
+	assert.Nil(t, sc.acquireMigrationLock())
+	assert.Nil(t, sc.releaseMigrationLock())
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// structFieldMapCache caches the db-column -> field-index map per struct type.
+var structFieldMapCache sync.Map // map[reflect.Type]map[string]int
+
+// fieldMapFor builds and caches the db-column -> field-index map for t. ok is
+// false unless every exported field of t carries a `db` tag (or is explicitly
+// excluded with `db:"-"`), telling the caller to fall back to mapstructure.
+// A partially-tagged struct would otherwise leave its untagged fields zero
+// because mapstructure's name-based matching never runs for it.
+func fieldMapFor(t reflect.Type) (fieldMap map[string]int, ok bool) {
+	if cached, found := structFieldMapCache.Load(t); found {
+		entry := cached.(fieldMapCacheEntry)
+		return entry.fieldMap, entry.ok
+	}
+	fieldMap = make(map[string]int)
+	ok = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		tag, tagged := field.Tag.Lookup("db")
+		if !tagged {
+			ok = false
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+		fieldMap[tag] = i
+	}
+	if len(fieldMap) == 0 {
+		ok = false
+	}
+	structFieldMapCache.Store(t, fieldMapCacheEntry{fieldMap: fieldMap, ok: ok})
+	return fieldMap, ok
+}
+
+type fieldMapCacheEntry struct {
+	fieldMap map[string]int
+	ok       bool
+}
+
+// decodeRowsByTag decodes results into input, a pointer to a slice of struct
+// or *struct, using `db:"col_name"` tags. handled is false when it can't,
+// telling the caller to fall back to mapstructure.
+func decodeRowsByTag(results []map[string]interface{}, input interface{}) (handled bool, err error) {
+	sliceValue := reflect.ValueOf(input)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.Elem().Kind() != reflect.Slice {
+		return false, nil
+	}
+	sliceValue = sliceValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return false, nil
+	}
+	fieldMap, ok := fieldMapFor(structType)
+	if !ok {
+		return false, nil
+	}
+	out := reflect.MakeSlice(sliceValue.Type(), 0, len(results))
+	for _, row := range results {
+		structValue := reflect.New(structType).Elem()
+		if err := decodeRowByTag(row, structValue, fieldMap); err != nil {
+			return true, err
+		}
+		if isPtr {
+			ptrValue := reflect.New(structType)
+			ptrValue.Elem().Set(structValue)
+			out = reflect.Append(out, ptrValue)
+		} else {
+			out = reflect.Append(out, structValue)
+		}
+	}
+	sliceValue.Set(out)
+	return true, nil
+}
+
+// decodeRowByTagInput decodes row into input, a pointer to struct, using
+// `db:"col_name"` tags. handled is false when it can't, telling the caller
+// to fall back to mapstructure.
+func decodeRowByTagInput(row map[string]interface{}, input interface{}) (handled bool, err error) {
+	ptrValue := reflect.ValueOf(input)
+	if ptrValue.Kind() != reflect.Ptr || ptrValue.Elem().Kind() != reflect.Struct {
+		return false, nil
+	}
+	structValue := ptrValue.Elem()
+	fieldMap, ok := fieldMapFor(structValue.Type())
+	if !ok {
+		return false, nil
+	}
+	return true, decodeRowByTag(row, structValue, fieldMap)
+}
+
+func decodeRowByTag(row map[string]interface{}, structValue reflect.Value, fieldMap map[string]int) error {
+	for column, value := range row {
+		index, ok := fieldMap[column]
+		if !ok {
+			continue
+		}
+		if err := setFieldValue(structValue.Field(index), value); err != nil {
+			return fmt.Errorf("column %s: %v", column, err)
+		}
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// setFieldValue assigns value, as returned by scanRows, to field. It defers
+// to Scan for an sql.Scanner field, allocates through a nil pointer field,
+// and otherwise converts directly, with a special case for time.Time.
+func setFieldValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(scannerType) {
+		return field.Addr().Interface().(sql.Scanner).Scan(value)
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setFieldValue(field.Elem(), value)
+	}
+	if field.Type() == timeType {
+		switch v := value.(type) {
+		case time.Time:
+			field.Set(reflect.ValueOf(v))
+			return nil
+		case string:
+			t, err := time.Parse("2006-01-02 15:04:05", v)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(t))
+			return nil
+		default:
+			return fmt.Errorf("cannot decode %T into time.Time", value)
+		}
+	}
+	if b, ok := value.([]byte); ok && field.Kind() == reflect.String {
+		field.SetString(string(b))
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	if !rv.Type().ConvertibleTo(field.Type()) {
+		return fmt.Errorf("cannot decode %T into %s", value, field.Type())
+	}
+	field.Set(rv.Convert(field.Type()))
+	return nil
+}
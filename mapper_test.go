@@ -0,0 +1,85 @@
+package client
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapperTestRow struct {
+	Id      int64          `db:"id"`
+	Name    *string        `db:"name"`
+	Nick    sql.NullString `db:"nick"`
+	Created time.Time      `db:"created_time"`
+}
+
+func TestDecodeRowByTagInput_PointerField(t *testing.T) {
+	row := map[string]interface{}{
+		"id":           int64(1),
+		"name":         "alice",
+		"nick":         nil,
+		"created_time": "2020-01-02 15:04:05",
+	}
+	var out mapperTestRow
+	handled, err := decodeRowByTagInput(row, &out)
+	assert.True(t, handled)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), out.Id)
+	assert.NotNil(t, out.Name)
+	assert.Equal(t, "alice", *out.Name)
+	assert.False(t, out.Nick.Valid)
+	assert.Equal(t, 2020, out.Created.Year())
+}
+
+func TestDecodeRowByTagInput_ScannerField(t *testing.T) {
+	row := map[string]interface{}{
+		"nick": "bob",
+	}
+	var out mapperTestRow
+	handled, err := decodeRowByTagInput(row, &out)
+	assert.True(t, handled)
+	assert.Nil(t, err)
+	assert.True(t, out.Nick.Valid)
+	assert.Equal(t, "bob", out.Nick.String)
+}
+
+func TestDecodeRowsByTag_PointerSlice(t *testing.T) {
+	results := []map[string]interface{}{
+		{"id": int64(1), "name": "alice"},
+		{"id": int64(2), "name": nil},
+	}
+	var out []mapperTestRow
+	handled, err := decodeRowsByTag(results, &out)
+	assert.True(t, handled)
+	assert.Nil(t, err)
+	assert.Len(t, out, 2)
+	assert.Equal(t, "alice", *out[0].Name)
+	assert.Nil(t, out[1].Name)
+}
+
+func TestSetFieldValue_NilValueLeavesPointerNil(t *testing.T) {
+	var out mapperTestRow
+	_, err := decodeRowByTagInput(map[string]interface{}{"name": nil}, &out)
+	assert.Nil(t, err)
+	assert.Nil(t, out.Name)
+}
+
+type partiallyTaggedRow struct {
+	ID   int64 `db:"id"`
+	Name string
+}
+
+func TestFieldMapFor_PartiallyTaggedStructFallsBackToMapstructure(t *testing.T) {
+	_, ok := fieldMapFor(reflect.TypeOf(partiallyTaggedRow{}))
+	assert.False(t, ok)
+}
+
+func TestDecodeRowByTagInput_PartiallyTaggedStruct(t *testing.T) {
+	var out partiallyTaggedRow
+	handled, err := decodeRowByTagInput(map[string]interface{}{"id": int64(1), "name": "alice"}, &out)
+	assert.False(t, handled)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,251 @@
+package client
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// matches e.g. "V001__create_users.up.sql" / "V001__create_users.down.sql".
+var migrationFilePattern = regexp.MustCompile(`^V(\d+)__(.+)\.(up|down)\.sql$`)
+
+type migrationFile struct {
+	Version     int64
+	Description string
+	Direction   string
+	Name        string
+}
+
+func parseMigrationFile(name string) (migrationFile, bool) {
+	m := migrationFilePattern.FindStringSubmatch(name)
+	if m == nil {
+		return migrationFile{}, false
+	}
+	version, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return migrationFile{}, false
+	}
+	return migrationFile{
+		Version:     version,
+		Description: m[2],
+		Direction:   m[3],
+		Name:        name,
+	}, true
+}
+
+func (sc *SqliteClient) loadMigrationFiles() ([]migrationFile, error) {
+	names, err := sc.config.source.List()
+	if err != nil {
+		return nil, err
+	}
+	var migrations []migrationFile
+	for _, name := range names {
+		mf, ok := parseMigrationFile(name)
+		if !ok {
+			logrus.Warnf("skip file that does not look like a migration. %s", name)
+			continue
+		}
+		migrations = append(migrations, mf)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func findMigrationFile(migrations []migrationFile, version int64, direction string) (migrationFile, bool) {
+	for _, mf := range migrations {
+		if mf.Version == version && mf.Direction == direction {
+			return mf, true
+		}
+	}
+	return migrationFile{}, false
+}
+
+func isVersionApplied(version int64, svArray []SchemaVersion) bool {
+	for _, applied := range appliedVersions(svArray) {
+		if applied == version {
+			return true
+		}
+	}
+	return false
+}
+
+// latestUpRecord finds the migration's latest successful "up" record, used
+// to detect a changed file checksum.
+func latestUpRecord(version int64, svArray []SchemaVersion) (SchemaVersion, bool) {
+	var latest SchemaVersion
+	found := false
+	for _, sv := range svArray {
+		if sv.Version != version || sv.Direction != DirectionUp || sv.Status != SchemaVersionStatusSuccess {
+			continue
+		}
+		if !found || sv.Id > latest.Id {
+			latest = sv
+			found = true
+		}
+	}
+	return latest, found
+}
+
+func distinctVersions(svArray []SchemaVersion) []int64 {
+	seen := make(map[int64]bool)
+	var versions []int64
+	for _, sv := range svArray {
+		if !seen[sv.Version] {
+			seen[sv.Version] = true
+			versions = append(versions, sv.Version)
+		}
+	}
+	return versions
+}
+
+// latestRecordByVersion returns version's most recent schema_version row
+// across both directions.
+func latestRecordByVersion(version int64, svArray []SchemaVersion) (SchemaVersion, bool) {
+	var latest SchemaVersion
+	found := false
+	for _, sv := range svArray {
+		if sv.Version != version {
+			continue
+		}
+		if !found || sv.Id > latest.Id {
+			latest = sv
+			found = true
+		}
+	}
+	return latest, found
+}
+
+// appliedVersions returns the currently applied versions, sorted ascending.
+func appliedVersions(svArray []SchemaVersion) []int64 {
+	netApplied := make(map[int64]int)
+	for _, sv := range svArray {
+		if sv.Status != SchemaVersionStatusSuccess {
+			continue
+		}
+		switch sv.Direction {
+		case DirectionUp:
+			netApplied[sv.Version]++
+		case DirectionDown:
+			netApplied[sv.Version]--
+		}
+	}
+	var versions []int64
+	for version, count := range netApplied {
+		if count > 0 {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+// Migrate applies every pending "up" migration up to targetVersion.
+func (sc *SqliteClient) Migrate(targetVersion int64) error {
+	if sc.config.source == nil {
+		return fmt.Errorf("no migration source configured; set DDLPath or Migrations")
+	}
+	if err := sc.acquireMigrationLock(); err != nil {
+		return err
+	}
+	defer sc.releaseMigrationLock()
+	migrations, err := sc.loadMigrationFiles()
+	if err != nil {
+		return err
+	}
+	svArray, err := sc.SchemaVersionArray()
+	if err != nil {
+		return err
+	}
+	if err := sc.hasError(svArray); err != nil {
+		return err
+	}
+	for _, mf := range migrations {
+		if mf.Direction != DirectionUp || mf.Version > targetVersion {
+			continue
+		}
+		if err := sc.applyMigration(mf, svArray); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback undoes the `steps` most recently applied migrations.
+func (sc *SqliteClient) Rollback(steps int) error {
+	if steps <= 0 {
+		return nil
+	}
+	if sc.config.source == nil {
+		return fmt.Errorf("no migration source configured; set DDLPath or Migrations")
+	}
+	if err := sc.acquireMigrationLock(); err != nil {
+		return err
+	}
+	defer sc.releaseMigrationLock()
+	migrations, err := sc.loadMigrationFiles()
+	if err != nil {
+		return err
+	}
+	svArray, err := sc.SchemaVersionArray()
+	if err != nil {
+		return err
+	}
+	if err := sc.hasError(svArray); err != nil {
+		return err
+	}
+	applied := appliedVersions(svArray)
+	if len(applied) > steps {
+		applied = applied[len(applied)-steps:]
+	}
+	for i := len(applied) - 1; i >= 0; i-- {
+		version := applied[i]
+		mf, ok := findMigrationFile(migrations, version, DirectionDown)
+		if !ok {
+			return fmt.Errorf("no down script found for version %d", version)
+		}
+		content, err := sc.config.source.Read(mf.Name)
+		if err != nil {
+			return err
+		}
+		if err := sc.execMigrationTx(mf, sha256Hex(string(content)), string(content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Force marks version as applied without running its migration script, to
+// unstick a version stuck in ERROR status after fixing the schema by hand.
+func (sc *SqliteClient) Force(version int64) error {
+	if err := sc.acquireMigrationLock(); err != nil {
+		return err
+	}
+	defer sc.releaseMigrationLock()
+	sc.insertSchemaVersion(SchemaVersion{
+		Version:       version,
+		Description:   "forced",
+		Direction:     DirectionUp,
+		Script:        "force",
+		Checksum:      "",
+		ExecutionTime: "0s",
+		Status:        SchemaVersionStatusSuccess,
+	})
+	return nil
+}
+
+// Status returns the highest applied migration version, or 0 if none.
+func (sc *SqliteClient) Status() (int64, error) {
+	svArray, err := sc.SchemaVersionArray()
+	if err != nil {
+		return 0, err
+	}
+	applied := appliedVersions(svArray)
+	if len(applied) == 0 {
+		return 0, nil
+	}
+	return applied[len(applied)-1], nil
+}
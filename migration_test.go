@@ -0,0 +1,139 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMigrationFile(t *testing.T) {
+	mf, ok := parseMigrationFile("V001__create_users.up.sql")
+	assert.True(t, ok)
+	assert.Equal(t, migrationFile{Version: 1, Description: "create_users", Direction: DirectionUp, Name: "V001__create_users.up.sql"}, mf)
+
+	mf, ok = parseMigrationFile("V002__create_users.down.sql")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), mf.Version)
+	assert.Equal(t, DirectionDown, mf.Direction)
+
+	_, ok = parseMigrationFile("README.md")
+	assert.False(t, ok)
+}
+
+func TestAppliedVersions(t *testing.T) {
+	svArray := []SchemaVersion{
+		{Id: 1, Version: 1, Direction: DirectionUp, Status: SchemaVersionStatusSuccess},
+		{Id: 2, Version: 2, Direction: DirectionUp, Status: SchemaVersionStatusSuccess},
+		{Id: 3, Version: 2, Direction: DirectionDown, Status: SchemaVersionStatusSuccess},
+		{Id: 4, Version: 3, Direction: DirectionUp, Status: SchemaVersionStatusError},
+	}
+	assert.Equal(t, []int64{1}, appliedVersions(svArray))
+}
+
+func TestIsVersionApplied(t *testing.T) {
+	svArray := []SchemaVersion{
+		{Id: 1, Version: 1, Direction: DirectionUp, Status: SchemaVersionStatusSuccess},
+		{Id: 2, Version: 2, Direction: DirectionUp, Status: SchemaVersionStatusSuccess},
+		{Id: 3, Version: 2, Direction: DirectionDown, Status: SchemaVersionStatusSuccess},
+	}
+	assert.True(t, isVersionApplied(1, svArray))
+	assert.False(t, isVersionApplied(2, svArray))
+	assert.False(t, isVersionApplied(3, svArray))
+}
+
+func TestLatestUpRecord(t *testing.T) {
+	svArray := []SchemaVersion{
+		{Id: 1, Version: 1, Direction: DirectionUp, Status: SchemaVersionStatusSuccess, Checksum: "old"},
+		{Id: 2, Version: 1, Direction: DirectionDown, Status: SchemaVersionStatusSuccess},
+		{Id: 3, Version: 1, Direction: DirectionUp, Status: SchemaVersionStatusSuccess, Checksum: "new"},
+	}
+	sv, found := latestUpRecord(1, svArray)
+	assert.True(t, found)
+	assert.Equal(t, "new", sv.Checksum)
+
+	_, found = latestUpRecord(2, svArray)
+	assert.False(t, found)
+}
+
+func TestHasError_ResolvedByForce(t *testing.T) {
+	client := &SqliteClient{}
+	errored := []SchemaVersion{
+		{Id: 1, Version: 3, Direction: DirectionUp, Status: SchemaVersionStatusError},
+	}
+	assert.Error(t, client.hasError(errored))
+
+	forced := append(errored, SchemaVersion{Id: 2, Version: 3, Direction: DirectionUp, Status: SchemaVersionStatusSuccess, Script: "force"})
+	assert.NoError(t, client.hasError(forced))
+}
+
+func TestMigrate_NoSourceConfigured(t *testing.T) {
+	client := &SqliteClient{config: &Config{}}
+	assert.Error(t, client.Migrate(1))
+}
+
+func TestRollback_NoSourceConfigured(t *testing.T) {
+	client := &SqliteClient{config: &Config{}}
+	assert.Error(t, client.Rollback(1))
+}
+
+func TestMigrate_DirSource_AppliesAndRollback(t *testing.T) {
+	dir := t.TempDir()
+	upSQL := `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);`
+	downSQL := `DROP TABLE users;`
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "V001__create_users.up.sql"), []byte(upSQL), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "V001__create_users.down.sql"), []byte(downSQL), 0644))
+
+	sc := NewSqliteClient(filepath.Join(t.TempDir(), "migrate.db"), DDLPath(dir), NoAutoMigrate())
+	assert.Nil(t, sc.Initial())
+
+	assert.Nil(t, sc.Migrate(1))
+	exist, err := sc.HasTable("users")
+	assert.Nil(t, err)
+	assert.True(t, exist)
+
+	svArray, err := sc.SchemaVersionArray()
+	assert.Nil(t, err)
+	sv, ok := latestUpRecord(1, svArray)
+	assert.True(t, ok)
+	assert.Equal(t, "create_users", sv.Description)
+	assert.Equal(t, sha256Hex(upSQL), sv.Checksum)
+	assert.Equal(t, SchemaVersionStatusSuccess, sv.Status)
+
+	assert.Nil(t, sc.Rollback(1))
+	exist, err = sc.HasTable("users")
+	assert.Nil(t, err)
+	assert.False(t, exist)
+
+	svArray, err = sc.SchemaVersionArray()
+	assert.Nil(t, err)
+	assert.Empty(t, appliedVersions(svArray))
+}
+
+func TestMigrate_FSSource_AppliesAndRollback(t *testing.T) {
+	upSQL := `CREATE TABLE posts (id INTEGER PRIMARY KEY, title TEXT NOT NULL);`
+	downSQL := `DROP TABLE posts;`
+	fs := fstest.MapFS{
+		"V001__create_posts.up.sql":   {Data: []byte(upSQL)},
+		"V001__create_posts.down.sql": {Data: []byte(downSQL)},
+	}
+
+	sc := NewSqliteClient(filepath.Join(t.TempDir(), "migrate-fs.db"), Migrations(FSSource{FS: fs}), NoAutoMigrate())
+	assert.Nil(t, sc.Initial())
+
+	assert.Nil(t, sc.Migrate(1))
+	exist, err := sc.HasTable("posts")
+	assert.Nil(t, err)
+	assert.True(t, exist)
+
+	svArray, err := sc.SchemaVersionArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1}, appliedVersions(svArray))
+
+	assert.Nil(t, sc.Rollback(1))
+	exist, err = sc.HasTable("posts")
+	assert.Nil(t, err)
+	assert.False(t, exist)
+}
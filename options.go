@@ -8,8 +8,13 @@ type Config struct {
 	connMaxLifetime time.Duration
 	attempts        uint
 	delay           time.Duration
-	ddlPath         string
+	source          MigrationSource
 	flyway          bool
+	autoMigrate     bool
+	dialect         Dialect
+	lockTimeout     time.Duration
+	lockRetry       uint
+	lockStaleAfter  time.Duration
 }
 
 type Option func(*Config)
@@ -44,11 +49,69 @@ func Delay(delay time.Duration) Option {
 	}
 }
 
+// DDLPath is sugar for MigrationSource(DirSource{Path: ddlPath}): it reads
+// versioned migration scripts from a directory on disk.
 func DDLPath(ddlPath string) Option {
 	return func(c *Config) {
-		c.ddlPath = ddlPath
 		if ddlPath != "" {
 			c.flyway = true
+			c.source = DirSource{Path: ddlPath}
 		}
 	}
 }
+
+// Migrations selects the MigrationSource used to read versioned migration
+// scripts, e.g. FSSource for an embed.FS or BindataSource for go-bindata
+// generated assets.
+func Migrations(source MigrationSource) Option {
+	return func(c *Config) {
+		c.source = source
+		c.flyway = true
+	}
+}
+
+// NoAutoMigrate disables the automatic "apply every pending migration" that
+// Initial() otherwise runs when a MigrationSource is configured. Use this
+// when the caller drives schema changes explicitly via Migrate/Rollback,
+// e.g. the sqlite-migrate CLI, so Initial() only prepares the schema_version
+// bookkeeping without applying anything on its own.
+func NoAutoMigrate() Option {
+	return func(c *Config) {
+		c.autoMigrate = false
+	}
+}
+
+// LockTimeout sets the delay between retries while waiting for another
+// process to release the migration lock.
+func LockTimeout(lockTimeout time.Duration) Option {
+	return func(c *Config) {
+		c.lockTimeout = lockTimeout
+	}
+}
+
+// LockRetry sets how many times to retry acquiring the migration lock before
+// giving up.
+func LockRetry(lockRetry uint) Option {
+	return func(c *Config) {
+		c.lockRetry = lockRetry
+	}
+}
+
+// LockStaleAfter sets how old a schema_lock sentinel row's acquired_at must
+// be before a future acquire attempt treats it as abandoned (e.g. the
+// process that took it was killed before it could release it) and reclaims
+// it instead of waiting it out.
+func LockStaleAfter(lockStaleAfter time.Duration) Option {
+	return func(c *Config) {
+		c.lockStaleAfter = lockStaleAfter
+	}
+}
+
+// WithDialect selects the Dialect used for connection setup and migrations.
+// It defaults to SQLiteDialect, so callers targeting SQLite don't need to set
+// this option at all.
+func WithDialect(dialect Dialect) Option {
+	return func(c *Config) {
+		c.dialect = dialect
+	}
+}
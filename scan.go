@@ -0,0 +1,51 @@
+package client
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// scanRows reads every remaining row into a []map[string]interface{},
+// preserving the driver's native Go type instead of coercing it to a string.
+func scanRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	var results []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		scans := make([]interface{}, len(columns))
+		for i := range values {
+			scans[i] = &values[i]
+		}
+		if err := rows.Scan(scans...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = normalizeScanValue(values[i], columnTypes[i])
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// normalizeScanValue decides, for a []byte value, whether it's text (surface
+// as string) or binary (keep as []byte), based on the column's type name.
+func normalizeScanValue(v interface{}, columnType *sql.ColumnType) interface{} {
+	b, ok := v.([]byte)
+	if !ok {
+		return v
+	}
+	switch strings.ToUpper(columnType.DatabaseTypeName()) {
+	case "BLOB", "BINARY", "VARBINARY", "BYTEA", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB":
+		return b
+	default:
+		return string(b)
+	}
+}
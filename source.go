@@ -0,0 +1,78 @@
+package client
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+)
+
+// MigrationSource abstracts where versioned migration scripts are read from,
+// so they can live on disk, be embedded in the binary via embed.FS, or be
+// generated bindata assets.
+type MigrationSource interface {
+	List() ([]string, error)
+	Read(name string) ([]byte, error)
+}
+
+// DirSource reads migration files from a directory on disk.
+type DirSource struct {
+	Path string
+}
+
+func (s DirSource) List() ([]string, error) {
+	files, err := ioutil.ReadDir(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		names = append(names, f.Name())
+	}
+	return names, nil
+}
+
+func (s DirSource) Read(name string) ([]byte, error) {
+	return ioutil.ReadFile(fmt.Sprintf("%s/%s", s.Path, name))
+}
+
+// FSSource reads migration files from an fs.FS, e.g. an embed.FS.
+type FSSource struct {
+	FS fs.FS
+}
+
+func (s FSSource) List() ([]string, error) {
+	entries, err := fs.ReadDir(s.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
+
+func (s FSSource) Read(name string) ([]byte, error) {
+	return fs.ReadFile(s.FS, name)
+}
+
+// BindataSource reads migration files from go-bindata-generated assets,
+// i.e. the AssetNames()/Asset(name) functions go-bindata produces.
+type BindataSource struct {
+	AssetNames func() []string
+	Asset      func(name string) ([]byte, error)
+}
+
+func (s BindataSource) List() ([]string, error) {
+	return s.AssetNames(), nil
+}
+
+func (s BindataSource) Read(name string) ([]byte, error) {
+	return s.Asset(name)
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSSource(t *testing.T) {
+	fs := fstest.MapFS{
+		"V001__create_users.up.sql":   {Data: []byte("create table users();")},
+		"V001__create_users.down.sql": {Data: []byte("drop table users;")},
+	}
+	source := FSSource{FS: fs}
+
+	names, err := source.List()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"V001__create_users.up.sql", "V001__create_users.down.sql"}, names)
+
+	content, err := source.Read("V001__create_users.up.sql")
+	assert.Nil(t, err)
+	assert.Equal(t, "create table users();", string(content))
+}
+
+func TestBindataSource(t *testing.T) {
+	assets := map[string][]byte{
+		"V001__create_users.up.sql": []byte("create table users();"),
+	}
+	source := BindataSource{
+		AssetNames: func() []string {
+			names := make([]string, 0, len(assets))
+			for name := range assets {
+				names = append(names, name)
+			}
+			return names
+		},
+		Asset: func(name string) ([]byte, error) {
+			return assets[name], nil
+		},
+	}
+
+	names, err := source.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"V001__create_users.up.sql"}, names)
+
+	content, err := source.Read("V001__create_users.up.sql")
+	assert.Nil(t, err)
+	assert.Equal(t, "create table users();", string(content))
+}